@@ -2,10 +2,13 @@ package agentconfig
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/yaml"
@@ -13,39 +16,17 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/types/agent"
 	"github.com/openshift/installer/pkg/types/agent/conversion"
+	"github.com/openshift/installer/pkg/types/agent/nmstate"
 	"github.com/openshift/installer/pkg/validate"
 )
 
 var (
 	agentConfigFilename = "agent-config.yaml"
-)
-
-// AgentConfig reads the agent-config.yaml file.
-type AgentConfig struct {
-	File     *asset.File
-	Config   *agent.Config
-	Template string
-}
-
-var _ asset.WritableAsset = (*AgentConfig)(nil)
-
-// Name returns a human friendly name for the asset.
-func (*AgentConfig) Name() string {
-	return "Agent Config"
-}
-
-// Dependencies returns all of the dependencies directly needed to generate
-// the asset.
-func (*AgentConfig) Dependencies() []asset.Asset {
-	return []asset.Asset{}
-}
-
-// Generate generates the Agent Config manifest.
-func (a *AgentConfig) Generate(dependencies asset.Parents) error {
 
-	// TODO: We are temporarily generating a template of the agent-config.yaml
-	// Change this when its interactive survey is implemented.
-	agentConfigTemplate := `#
+	// agentConfigSampleTemplate is the commented sample emitted when the
+	// user asks for --generate-template instead of running the survey, so
+	// that scripted users relying on today's output are unaffected.
+	agentConfigSampleTemplate = `#
 # Note: This is a sample AgentConfig file showing
 # which fields are available to aid you in creating your
 # own agent-config.yaml file.
@@ -85,13 +66,206 @@ hosts:
               prefix-length: 23
           dhcp: false
 `
+)
 
-	a.Template = agentConfigTemplate
+// GenerateTemplate controls whether Generate emits the commented sample
+// agent-config.yaml instead of running the interactive survey. It is set
+// by the owning command from the --generate-template flag.
+var GenerateTemplate bool
+
+// AlwaysByDefault controls whether Generate emits the commented sample
+// agent-config.yaml instead of running the interactive survey, the same
+// mechanism InstallConfig uses to skip its own survey for scripted and CI
+// callers that predate it. It is set by the owning command.
+var AlwaysByDefault bool
+
+// AgentConfig reads the agent-config.yaml file.
+type AgentConfig struct {
+	File     *asset.File
+	Config   *agent.Config
+	Template string
+}
+
+var _ asset.WritableAsset = (*AgentConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*AgentConfig) Name() string {
+	return "Agent Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*AgentConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates the Agent Config manifest. Unless --generate-template
+// was requested, it surveys the user for rendezvous IP, hosts and their
+// network configuration, the same way InstallConfig surveys for its
+// required fields, and validates the result before persisting it.
+// AlwaysByDefault mirrors InstallConfig's own escape hatch for that survey,
+// so scripted callers that predate this survey keep getting the commented
+// sample instead of being surveyed.
+func (a *AgentConfig) Generate(dependencies asset.Parents) error {
+	if GenerateTemplate || AlwaysByDefault {
+		a.Template = agentConfigSampleTemplate
+		return nil
+	}
+
+	config, err := a.survey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate agent-config.yaml")
+	}
+	a.Config = config
+
+	if err := a.validateAgent().ToAggregate(); err != nil {
+		return errors.Wrap(err, "invalid Agent Config configuration")
+	}
+
+	data, err := yaml.Marshal(a.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal agent-config.yaml")
+	}
+	a.Template = string(data)
 
-	// TODO: template is not validated
 	return nil
 }
 
+// survey interactively prompts for the fields of an agent.Config, mirroring
+// the InstallConfig asset's AlwaysByDefault-aware survey mechanism.
+func (a *AgentConfig) survey() (*agent.Config, error) {
+	config := &agent.Config{}
+	config.APIVersion = "v1alpha1"
+	config.Kind = "AgentConfig"
+
+	if err := survey.AskOne(&survey.Input{
+		Message: "Rendezvous IP",
+		Help:    "The IP address of the node used to bootstrap the cluster.",
+	}, &config.RendezvousIP, survey.WithValidator(survey.Required)); err != nil {
+		return nil, err
+	}
+
+	numMasters, err := askInt("Number of master hosts", 1)
+	if err != nil {
+		return nil, err
+	}
+	numWorkers, err := askInt("Number of worker hosts", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < numMasters+numWorkers; i++ {
+		role := "master"
+		if i >= numMasters {
+			role = "worker"
+		}
+
+		host, err := a.surveyHost(role)
+		if err != nil {
+			return nil, err
+		}
+		config.Hosts = append(config.Hosts, *host)
+	}
+
+	return config, nil
+}
+
+// surveyHost prompts for a single host's hostname, MAC address, root-device
+// hint and minimal network configuration.
+func (a *AgentConfig) surveyHost(role string) (*agent.Host, error) {
+	host := &agent.Host{Role: role}
+
+	if err := survey.AskOne(&survey.Input{Message: fmt.Sprintf("Hostname for %s host", role)}, &host.Hostname); err != nil {
+		return nil, err
+	}
+
+	var mac string
+	if err := survey.AskOne(&survey.Input{Message: "MAC address identifying this host"}, &mac, survey.WithValidator(func(ans interface{}) error {
+		return validate.MAC(ans.(string))
+	})); err != nil {
+		return nil, err
+	}
+	host.Interfaces = []agent.Interface{{Name: "eth0", MacAddress: mac}}
+
+	var deviceName string
+	if err := survey.AskOne(&survey.Input{Message: "Root device hint (e.g. /dev/sda)", Default: "/dev/sda"}, &deviceName); err != nil {
+		return nil, err
+	}
+	host.RootDeviceHints.DeviceName = deviceName
+
+	dhcp := false
+	if err := survey.AskOne(&survey.Confirm{Message: "Use DHCP for this host's network?", Default: true}, &dhcp); err != nil {
+		return nil, err
+	}
+
+	config := nmstate.Config{
+		Interfaces: []nmstate.Interface{
+			{
+				Name:       "eth0",
+				Type:       "ethernet",
+				State:      "up",
+				MacAddress: mac,
+				IPv4:       nmstate.IPConfig{Enabled: true, DHCP: dhcp},
+			},
+		},
+	}
+
+	if !dhcp {
+		var ip string
+		if err := survey.AskOne(&survey.Input{Message: "Static IPv4 or IPv6 address for this host"}, &ip, survey.WithValidator(func(ans interface{}) error {
+			return validate.IP(ans.(string))
+		})); err != nil {
+			return nil, err
+		}
+
+		var prefix int
+		prefixStr, err := askString("Prefix length", "24")
+		if err != nil {
+			return nil, err
+		}
+		prefix, err = strconv.Atoi(prefixStr)
+		if err != nil {
+			return nil, errors.Wrap(err, "prefix length must be a number")
+		}
+
+		address := nmstate.Address{IP: ip, PrefixLength: &prefix}
+		if ipFamily(ip) == "IPv6" {
+			config.Interfaces[0].IPv6 = nmstate.IPConfig{Enabled: true, Address: []nmstate.Address{address}}
+			config.Interfaces[0].IPv4 = nmstate.IPConfig{}
+		} else {
+			config.Interfaces[0].IPv4.Address = []nmstate.Address{address}
+		}
+	}
+
+	raw, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	host.NetworkConfig.Raw = raw
+
+	return host, nil
+}
+
+func askString(message, def string) (string, error) {
+	var answer string
+	if err := survey.AskOne(&survey.Input{Message: message, Default: def}, &answer); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+func askInt(message string, def int) (int, error) {
+	answer, err := askString(message, strconv.Itoa(def))
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%q must be a number", message)
+	}
+	return n, nil
+}
+
 // PersistToFile writes the agent-config.yaml file to the assets folder
 func (a *AgentConfig) PersistToFile(directory string) error {
 	templatePath := filepath.Join(directory, agentConfigFilename)
@@ -172,6 +346,20 @@ func (a *AgentConfig) validateAgent() field.ErrorList {
 	return allErrs
 }
 
+// rendezvousIPs splits the configured rendezvousIP into its constituent
+// addresses. A dual-stack rendezvousIP is expressed as a comma-separated
+// IPv4/IPv6 pair, mirroring the shift from status.podIP to status.podIPs
+// in the Kubernetes downward API.
+func rendezvousIPs(rendezvousIP string) []string {
+	var ips []string
+	for _, ip := range strings.Split(rendezvousIP, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
 func (a *AgentConfig) validateRendezvousIP() field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -182,17 +370,93 @@ func (a *AgentConfig) validateRendezvousIP() field.ErrorList {
 		return nil
 	}
 
-	if err := validate.IP(a.Config.RendezvousIP); err != nil {
-		allErrs = append(allErrs, field.Invalid(rendezvousIPPath, a.Config.RendezvousIP, err.Error()))
+	ips := rendezvousIPs(a.Config.RendezvousIP)
+	if len(ips) == 0 {
+		allErrs = append(allErrs, field.Invalid(rendezvousIPPath, a.Config.RendezvousIP, "rendezvousIP does not contain any address"))
+		return allErrs
+	}
+	if len(ips) > 2 {
+		allErrs = append(allErrs, field.Invalid(rendezvousIPPath, a.Config.RendezvousIP, "rendezvousIP must be a single address or an IPv4/IPv6 dual-stack pair"))
+		return allErrs
+	}
+
+	families := make(map[string]bool)
+	for _, ip := range ips {
+		if err := validate.IP(ip); err != nil {
+			allErrs = append(allErrs, field.Invalid(rendezvousIPPath, ip, err.Error()))
+			continue
+		}
+		families[ipFamily(ip)] = true
+	}
+
+	if len(ips) == 2 && len(families) != 2 {
+		allErrs = append(allErrs, field.Invalid(rendezvousIPPath, a.Config.RendezvousIP, "a dual-stack rendezvousIP must contain one IPv4 and one IPv6 address"))
+	}
+
+	if enabled := clusterEnabledFamilies(a.Config.Hosts); enabled != nil {
+		for family := range families {
+			if family != "" && !enabled[family] {
+				allErrs = append(allErrs, field.Invalid(rendezvousIPPath, a.Config.RendezvousIP,
+					fmt.Sprintf("rendezvousIP mixes families: %s is not enabled by any host's networkConfig", family)))
+			}
+		}
 	}
 
 	return allErrs
 }
 
+// clusterEnabledFamilies scans every host's NMState networkConfig for
+// interfaces with ipv4 or ipv6 enabled, and returns the set of address
+// families the cluster's network config enables. It returns nil when no
+// host declares a networkConfig at all, since there is then no cluster
+// network config to validate the rendezvousIP family against.
+func clusterEnabledFamilies(hosts []agent.Host) map[string]bool {
+	var enabled map[string]bool
+
+	for _, host := range hosts {
+		if len(host.NetworkConfig.Raw) == 0 {
+			continue
+		}
+		config, err := parseHostNetworkConfig(host.NetworkConfig)
+		if err != nil {
+			continue
+		}
+		if enabled == nil {
+			enabled = make(map[string]bool)
+		}
+		for _, iface := range config.Interfaces {
+			if iface.IPv4.Enabled {
+				enabled["IPv4"] = true
+			}
+			if iface.IPv6.Enabled {
+				enabled["IPv6"] = true
+			}
+		}
+	}
+
+	return enabled
+}
+
+// ipFamily returns "IPv4" or "IPv6" for a parsed IP address, or "" if ip
+// cannot be parsed.
+func ipFamily(ip string) string {
+	parsed := net.ParseIP(ip)
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.To4() != nil:
+		return "IPv4"
+	default:
+		return "IPv6"
+	}
+}
+
 func (a *AgentConfig) validateHosts() field.ErrorList {
 	var allErrs field.ErrorList
 
 	macs := make(map[string]bool)
+	bmcAddresses := make(map[string]bool)
+	bootMACs := make(map[string]bool)
 	for i, host := range a.Config.Hosts {
 
 		hostPath := field.NewPath("Hosts").Index(i)
@@ -208,6 +472,14 @@ func (a *AgentConfig) validateHosts() field.ErrorList {
 		if err := a.validateRoles(hostPath, host); err != nil {
 			allErrs = append(allErrs, err...)
 		}
+
+		if err := a.validateNetworkConfig(hostPath, host); err != nil {
+			allErrs = append(allErrs, err...)
+		}
+
+		if err := a.validateHostBMC(hostPath, host, bmcAddresses, bootMACs); err != nil {
+			allErrs = append(allErrs, err...)
+		}
 	}
 
 	return allErrs
@@ -243,6 +515,34 @@ func (a *AgentConfig) validateHostInterfaces(hostPath *field.Path, host agent.Ho
 	return allErrs
 }
 
+// validateHostBMC checks that a host's BMC address, if set, and the
+// BareMetalHost manifest's bootMACAddress, derived from the host's first
+// interface MAC, are not reused by another host. bootMACs is keyed on the
+// lowercased MAC, as the manifest writes it, so MACs that differ only in
+// letter case are reported as duplicates.
+func (a *AgentConfig) validateHostBMC(hostPath *field.Path, host agent.Host, bmcAddresses map[string]bool, bootMACs map[string]bool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if bootMAC := bootMACAddress(host); bootMAC != "" {
+		if bootMACs[bootMAC] {
+			allErrs = append(allErrs, field.Invalid(hostPath.Child("Interfaces").Index(0).Child("macAddress"), host.Interfaces[0].MacAddress, "duplicate bootMACAddress found"))
+		}
+		bootMACs[bootMAC] = true
+	}
+
+	if host.BMC.Address == "" {
+		return allErrs
+	}
+
+	bmcAddressPath := hostPath.Child("BMC", "address")
+	if bmcAddresses[host.BMC.Address] {
+		allErrs = append(allErrs, field.Invalid(bmcAddressPath, host.BMC.Address, "duplicate BMC address found"))
+	}
+	bmcAddresses[host.BMC.Address] = true
+
+	return allErrs
+}
+
 func (a *AgentConfig) validateHostRootDeviceHints(hostPath *field.Path, host agent.Host) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -268,6 +568,135 @@ func (a *AgentConfig) validateRoles(hostPath *field.Path, host agent.Host) field
 	return allErrs
 }
 
+// validateNetworkConfig unmarshals a host's networkConfig into the typed
+// NMState schema and checks it for internal consistency, so that mistakes
+// are caught at agent-config load time rather than when nmstatectl runs on
+// the node.
+func (a *AgentConfig) validateNetworkConfig(hostPath *field.Path, host agent.Host) field.ErrorList {
+	var allErrs field.ErrorList
+
+	networkConfigPath := hostPath.Child("networkConfig")
+
+	if len(host.NetworkConfig.Raw) == 0 {
+		return allErrs
+	}
+
+	config, err := parseHostNetworkConfig(host.NetworkConfig)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(networkConfigPath, host.Hostname, err.Error()))
+		return allErrs
+	}
+
+	// declaredIfaces and declaredMACs must be fully populated before any
+	// reference to them is validated below, since a bond or bridge is free
+	// to list a member interface that appears later in config.Interfaces.
+	declaredIfaces := make(map[string]bool)
+	declaredMACs := make(map[string]bool)
+	for _, iface := range config.Interfaces {
+		declaredIfaces[iface.Name] = true
+		if iface.MacAddress != "" {
+			declaredMACs[strings.ToLower(iface.MacAddress)] = true
+		}
+	}
+
+	var subnets []*net.IPNet
+
+	for i, iface := range config.Interfaces {
+		ifacePath := networkConfigPath.Child("interfaces").Index(i)
+
+		for _, ipConfig := range []struct {
+			family  string
+			ip      nmstate.IPConfig
+			maxBits int
+		}{
+			{"ipv4", iface.IPv4, 32},
+			{"ipv6", iface.IPv6, 128},
+		} {
+			for j, addr := range ipConfig.ip.Address {
+				addrPath := ifacePath.Child(ipConfig.family, "address").Index(j)
+
+				if addr.PrefixLength == nil {
+					allErrs = append(allErrs, field.Required(addrPath.Child("prefix-length"),
+						fmt.Sprintf("prefix length must be set for %s", ipConfig.family)))
+					continue
+				}
+
+				prefixLength := *addr.PrefixLength
+				if prefixLength < 0 || prefixLength > ipConfig.maxBits {
+					allErrs = append(allErrs, field.Invalid(addrPath.Child("prefix-length"), prefixLength,
+						fmt.Sprintf("prefix length must be between 0 and %d for %s", ipConfig.maxBits, ipConfig.family)))
+					continue
+				}
+
+				if _, subnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", addr.IP, prefixLength)); err == nil {
+					subnets = append(subnets, subnet)
+				}
+			}
+		}
+
+		if iface.Bond != nil {
+			for _, port := range iface.Bond.Port {
+				if !declaredIfaces[port] && port != iface.Name {
+					allErrs = append(allErrs, field.Invalid(ifacePath.Child("link-aggregation", "port"), port, "interface is not declared in networkConfig.interfaces"))
+				}
+			}
+		}
+
+		if iface.Bridge != nil {
+			for _, port := range iface.Bridge.Port {
+				if !declaredIfaces[port.Name] && port.Name != iface.Name {
+					allErrs = append(allErrs, field.Invalid(ifacePath.Child("bridge", "port"), port.Name, "interface is not declared in networkConfig.interfaces"))
+				}
+			}
+		}
+
+		if iface.Vlan != nil && iface.Vlan.BaseIface != "" && !declaredIfaces[iface.Vlan.BaseIface] {
+			allErrs = append(allErrs, field.Invalid(ifacePath.Child("vlan", "base-iface"), iface.Vlan.BaseIface, "interface is not declared in networkConfig.interfaces"))
+		}
+	}
+
+	for _, hostIface := range host.Interfaces {
+		if hostIface.MacAddress == "" {
+			continue
+		}
+		if !declaredMACs[strings.ToLower(hostIface.MacAddress)] {
+			allErrs = append(allErrs, field.Invalid(networkConfigPath.Child("interfaces"), hostIface.MacAddress,
+				"MAC address used to bind this host does not appear on any interface in networkConfig"))
+		}
+	}
+
+	for i, route := range config.Routes.Config {
+		routePath := networkConfigPath.Child("routes", "config").Index(i)
+
+		if route.NextHopIface != "" && !declaredIfaces[route.NextHopIface] {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("next-hop-interface"), route.NextHopIface, "interface is not declared in networkConfig.interfaces"))
+		}
+
+		if route.NextHopAddr == "" {
+			continue
+		}
+
+		gateway := net.ParseIP(route.NextHopAddr)
+		if gateway == nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("next-hop-address"), route.NextHopAddr, "not a valid IP address"))
+			continue
+		}
+
+		inSubnet := false
+		for _, subnet := range subnets {
+			if subnet.Contains(gateway) {
+				inSubnet = true
+				break
+			}
+		}
+		if !inSubnet {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("next-hop-address"), route.NextHopAddr, "gateway address does not lie within any declared subnet"))
+		}
+	}
+
+	return allErrs
+}
+
 func (a *AgentConfig) validateAdditionalNTPSources(additionalNTPSourcesPath *field.Path, sources []string) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -284,18 +713,75 @@ func (a *AgentConfig) validateAdditionalNTPSources(additionalNTPSourcesPath *fie
 	return allErrs
 }
 
+// hasIP reports whether any interface in the NMState config owns the given
+// IPv4 or IPv6 address.
+func hasIP(config nmstate.Config, ip string) bool {
+	for _, iface := range config.Interfaces {
+		for _, addr := range iface.IPv4.Address {
+			if addr.IP == ip {
+				return true
+			}
+		}
+		for _, addr := range iface.IPv6.Address {
+			if addr.IP == ip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseHostNetworkConfig(networkConfig agent.NetworkConfig) (nmstate.Config, error) {
+	var config nmstate.Config
+	if len(networkConfig.Raw) == 0 {
+		return config, nil
+	}
+	if err := yaml.Unmarshal(networkConfig.Raw, &config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
 func (a *AgentConfig) validateRendevousIPNotWorker(rendezvousIP string, hosts []agent.Host) field.ErrorList {
 	var allErrs field.ErrorList
 
-	if rendezvousIP != "" {
+	if rendezvousIP == "" {
+		return allErrs
+	}
+
+	rendezvousIPPath := field.NewPath("rendezvousIP")
+
+	for _, ip := range rendezvousIPs(rendezvousIP) {
+		family := ipFamily(ip)
+		ownedByMaster := false
+
 		for i, host := range hosts {
 			hostPath := field.NewPath("Hosts").Index(i)
-			if strings.Contains(string(host.NetworkConfig.Raw), rendezvousIP) && host.Role != "master" {
-				if len(host.Role) > 0 {
-					errMsg := "Host " + host.Hostname + " is not of role 'master' and has the rendevousIP assigned to it. The rendevousIP must be assigned to a host of role 'master'"
-					allErrs = append(allErrs, field.Forbidden(hostPath.Child("Host"), errMsg))
-				}
+
+			networkConfig, err := parseHostNetworkConfig(host.NetworkConfig)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(hostPath.Child("networkConfig"), host.Hostname, err.Error()))
+				continue
+			}
+
+			if !hasIP(networkConfig, ip) {
+				continue
 			}
+
+			if host.Role != "" && host.Role != "master" {
+				errMsg := "Host " + host.Hostname + " is not of role 'master' and has the rendevousIP assigned to it. The rendevousIP must be assigned to a host of role 'master'"
+				allErrs = append(allErrs, field.Forbidden(hostPath.Child("Host"), errMsg))
+				continue
+			}
+
+			// A host with no role declared has not been ruled out as a
+			// master yet, so it can still satisfy the "assigned to a
+			// master" requirement below.
+			ownedByMaster = true
+		}
+
+		if !ownedByMaster && family != "" {
+			allErrs = append(allErrs, field.Invalid(rendezvousIPPath, ip, fmt.Sprintf("no master host declares this %s address in its networkConfig", family)))
 		}
 	}
 
@@ -306,19 +792,29 @@ func (a *AgentConfig) validateRendevousIPNotWorker(rendezvousIP string, hosts []
 // for hostconfig files.
 type HostConfigFileMap map[string][]byte
 
+// hostConfigName returns the directory name used for a host's config files,
+// falling back to a positional name when no hostname is set.
+func hostConfigName(i int, host agent.Host) string {
+	if host.Hostname != "" {
+		return host.Hostname
+	}
+	return fmt.Sprintf("host-%d", i)
+}
+
 // HostConfigFiles returns a map from filename to contents of the files used for
-// host-specific configuration by the agent installer client
-func (a *AgentConfig) HostConfigFiles() (HostConfigFileMap, error) {
+// host-specific configuration by the agent installer client. When bmHosts is
+// non-nil, its BareMetalHost and Secret manifests are merged in under
+// <host>/manifests/ alongside the mac_addresses, role and root-device-hints
+// files, so the agent installer client can apply all of a host's config in
+// one pass.
+func (a *AgentConfig) HostConfigFiles(bmHosts *AgentBareMetalHosts) (HostConfigFileMap, error) {
 	if a == nil || a.Config == nil {
 		return nil, nil
 	}
 
 	files := HostConfigFileMap{}
 	for i, host := range a.Config.Hosts {
-		name := fmt.Sprintf("host-%d", i)
-		if host.Hostname != "" {
-			name = host.Hostname
-		}
+		name := hostConfigName(i, host)
 
 		macs := []string{}
 		for _, iface := range host.Interfaces {
@@ -340,7 +836,18 @@ func (a *AgentConfig) HostConfigFiles() (HostConfigFileMap, error) {
 		if len(host.Role) > 0 {
 			files[filepath.Join(name, "role")] = []byte(host.Role)
 		}
+
+		if len(host.NetworkConfig.Raw) > 0 {
+			files[filepath.Join(name, "networkconfig.yaml")] = host.NetworkConfig.Raw
+		}
 	}
+
+	if bmHosts != nil {
+		for path, content := range bmHosts.Hosts {
+			files[path] = content
+		}
+	}
+
 	return files, nil
 }
 
@@ -0,0 +1,166 @@
+package agentconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+func networkConfigFor(raw string) agent.NetworkConfig {
+	return agent.NetworkConfig{RawExtension: runtime.RawExtension{Raw: []byte(raw)}}
+}
+
+func TestValidateRendezvousIP(t *testing.T) {
+	cases := []struct {
+		name         string
+		rendezvousIP string
+		hosts        []agent.Host
+		wantErr      string
+	}{
+		{
+			name:         "empty rendezvousIP is valid",
+			rendezvousIP: "",
+		},
+		{
+			name:         "single valid address",
+			rendezvousIP: "192.168.122.2",
+		},
+		{
+			name:         "garbage address is rejected",
+			rendezvousIP: "not-an-ip",
+			wantErr:      "rendezvousIP",
+		},
+		{
+			name:         "dual-stack pair of the same family is rejected",
+			rendezvousIP: "192.168.122.2,192.168.122.3",
+			wantErr:      "must contain one IPv4 and one IPv6",
+		},
+		{
+			name:         "more than two addresses is rejected",
+			rendezvousIP: "192.168.122.2,192.168.122.3,::1",
+			wantErr:      "single address or an IPv4/IPv6 dual-stack pair",
+		},
+		{
+			name:         "only commas is rejected",
+			rendezvousIP: ",,",
+			wantErr:      "does not contain any address",
+		},
+		{
+			name:         "family not enabled by any host's networkConfig is rejected",
+			rendezvousIP: "2001:db8::1",
+			hosts: []agent.Host{
+				{
+					Hostname: "master-0",
+					NetworkConfig: networkConfigFor(`interfaces:
+- name: eth0
+  ipv4:
+    enabled: true
+`),
+				},
+			},
+			wantErr: "is not enabled by any host's networkConfig",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &AgentConfig{Config: &agent.Config{RendezvousIP: tc.rendezvousIP, Hosts: tc.hosts}}
+			errs := a.validateRendezvousIP()
+
+			if tc.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.NotEmpty(t, errs)
+			assert.Contains(t, errs.ToAggregate().Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestValidateRendevousIPNotWorker(t *testing.T) {
+	masterWithIP := agent.Host{
+		Hostname: "master-0",
+		Role:     "master",
+		NetworkConfig: networkConfigFor(`interfaces:
+- name: eth0
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.168.122.2
+      prefix-length: 24
+`),
+	}
+	unroledHostWithIP := agent.Host{
+		Hostname: "host-0",
+		NetworkConfig: networkConfigFor(`interfaces:
+- name: eth0
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.168.122.2
+      prefix-length: 24
+`),
+	}
+	workerWithIP := agent.Host{
+		Hostname: "worker-0",
+		Role:     "worker",
+		NetworkConfig: networkConfigFor(`interfaces:
+- name: eth0
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.168.122.2
+      prefix-length: 24
+`),
+	}
+
+	cases := []struct {
+		name         string
+		rendezvousIP string
+		hosts        []agent.Host
+		wantErr      string
+	}{
+		{
+			name:         "master owning the address is valid",
+			rendezvousIP: "192.168.122.2",
+			hosts:        []agent.Host{masterWithIP},
+		},
+		{
+			name:         "host with no role declared owning the address is valid",
+			rendezvousIP: "192.168.122.2",
+			hosts:        []agent.Host{unroledHostWithIP},
+		},
+		{
+			name:         "worker owning the address is rejected",
+			rendezvousIP: "192.168.122.2",
+			hosts:        []agent.Host{workerWithIP},
+			wantErr:      "must be assigned to a host of role 'master'",
+		},
+		{
+			name:         "no host owning the address is rejected",
+			rendezvousIP: "192.168.122.2",
+			hosts:        nil,
+			wantErr:      "no master host declares this",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &AgentConfig{}
+			errs := a.validateRendevousIPNotWorker(tc.rendezvousIP, tc.hosts)
+
+			if tc.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.NotEmpty(t, errs)
+			assert.Contains(t, errs.ToAggregate().Error(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package agentconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+const (
+	bareMetalHostAPIVersion = "metal3.io/v1alpha1"
+	clusterRoleLabel        = "metal3.io/cluster-role"
+)
+
+// AgentBareMetalHosts generates a metal3.io BareMetalHost and a corresponding
+// BMC credentials Secret for every host declared in AgentConfig, so the
+// agent installer client can hand them straight to the assisted-service
+// without a second translation step.
+type AgentBareMetalHosts struct {
+	Hosts HostConfigFileMap
+}
+
+var _ asset.Asset = (*AgentBareMetalHosts)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*AgentBareMetalHosts) Name() string {
+	return "Agent Bare Metal Hosts"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*AgentBareMetalHosts) Dependencies() []asset.Asset {
+	return []asset.Asset{&AgentConfig{}}
+}
+
+// Generate generates the BareMetalHost and Secret manifests for every host.
+func (a *AgentBareMetalHosts) Generate(dependencies asset.Parents) error {
+	agentConfig := &AgentConfig{}
+	dependencies.Get(agentConfig)
+
+	if agentConfig.Config == nil {
+		return nil
+	}
+
+	namespace := agentConfig.Config.Namespace
+
+	// Duplicate bootMACAddress/BMC addresses across hosts are already
+	// rejected by AgentConfig.validateAgent at load time, so this asset
+	// can trust agentConfig.Config without re-checking them here.
+	a.Hosts = HostConfigFileMap{}
+
+	for i, host := range agentConfig.Config.Hosts {
+		bootMAC := bootMACAddress(host)
+		name := hostConfigName(i, host)
+
+		bmhYAML, err := yaml.Marshal(newBareMetalHost(name, namespace, host, bootMAC))
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal BareMetalHost for host %s", name)
+		}
+		a.Hosts[filepath.Join(name, "manifests", name+"-bmh.yaml")] = bmhYAML
+
+		secretYAML, err := yaml.Marshal(newBMCSecret(name, namespace))
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal BMC Secret for host %s", name)
+		}
+		a.Hosts[filepath.Join(name, "manifests", name+"-bmc-secret.yaml")] = secretYAML
+	}
+
+	return nil
+}
+
+// bootMACAddress returns the MAC address used to identify the host, which
+// doubles as the BareMetalHost's bootMACAddress.
+func bootMACAddress(host agent.Host) string {
+	if len(host.Interfaces) == 0 {
+		return ""
+	}
+	return strings.ToLower(host.Interfaces[0].MacAddress)
+}
+
+// clusterRole maps an agent-config host role to the metal3.io/cluster-role
+// label value. Hosts with no role declared are left unlabeled.
+func clusterRole(role string) string {
+	switch role {
+	case "master", "worker":
+		return role
+	default:
+		return ""
+	}
+}
+
+type bareMetalHost struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   manifestMetadata  `json:"metadata"`
+	Spec       bareMetalHostSpec `json:"spec"`
+}
+
+type bareMetalHostSpec struct {
+	Online          bool                  `json:"online"`
+	BootMACAddress  string                `json:"bootMACAddress,omitempty"`
+	RootDeviceHints agent.RootDeviceHints `json:"rootDeviceHints,omitempty"`
+	BMC             bareMetalHostBMC      `json:"bmc,omitempty"`
+}
+
+type bareMetalHostBMC struct {
+	Address         string `json:"address,omitempty"`
+	CredentialsName string `json:"credentialsName,omitempty"`
+}
+
+type manifestMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type bmcSecret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   manifestMetadata  `json:"metadata"`
+	Type       string            `json:"type"`
+	StringData map[string]string `json:"stringData,omitempty"`
+}
+
+func newBareMetalHost(name, namespace string, host agent.Host, bootMAC string) bareMetalHost {
+	labels := map[string]string{}
+	if role := clusterRole(host.Role); role != "" {
+		labels[clusterRoleLabel] = role
+	}
+
+	return bareMetalHost{
+		APIVersion: bareMetalHostAPIVersion,
+		Kind:       "BareMetalHost",
+		Metadata: manifestMetadata{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: bareMetalHostSpec{
+			Online:          true,
+			BootMACAddress:  bootMAC,
+			RootDeviceHints: host.RootDeviceHints,
+			BMC: bareMetalHostBMC{
+				Address:         host.BMC.Address,
+				CredentialsName: fmt.Sprintf("%s-bmc-secret", name),
+			},
+		},
+	}
+}
+
+func newBMCSecret(name, namespace string) bmcSecret {
+	return bmcSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: manifestMetadata{
+			Name:      fmt.Sprintf("%s-bmc-secret", name),
+			Namespace: namespace,
+		},
+		Type: "Opaque",
+		StringData: map[string]string{
+			"username": "",
+			"password": "",
+		},
+	}
+}
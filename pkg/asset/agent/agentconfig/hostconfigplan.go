@@ -0,0 +1,230 @@
+package agentconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HostConfigKind identifies the kind of a single HostConfigPlan entry.
+type HostConfigKind string
+
+// Kinds of host config files known to the apply pipeline, in registration
+// order. Each kind's position here also fixes its tie-break order within a
+// phase, so Walk always visits entries in a stable order regardless of how
+// they were built.
+const (
+	KindMacAddresses   HostConfigKind = "mac_addresses"
+	KindRole           HostConfigKind = "role"
+	KindRootDeviceHint HostConfigKind = "root-device-hints"
+	KindNetworkConfig  HostConfigKind = "networkconfig"
+	KindBareMetalHost  HostConfigKind = "baremetalhost"
+)
+
+// HostConfigPhase groups host config kinds that can be applied together.
+type HostConfigPhase string
+
+// Phases of the apply pipeline, in the order a host must receive them: its
+// identity must be established before it can be classified, classified
+// before its hardware is configured, and its hardware known before its
+// network is configured.
+const (
+	PhaseIdentity       HostConfigPhase = "identity"
+	PhaseClassification HostConfigPhase = "classification"
+	PhaseHardware       HostConfigPhase = "hardware"
+	PhaseNetwork        HostConfigPhase = "network"
+)
+
+// kindOrder lists every known kind in the order they are registered, and
+// doubles as the traversal order used to build the dependency graph below.
+var kindOrder = []HostConfigKind{
+	KindMacAddresses,
+	KindRole,
+	KindRootDeviceHint,
+	KindNetworkConfig,
+	KindBareMetalHost,
+}
+
+// kindPhase assigns each kind to its phase.
+var kindPhase = map[HostConfigKind]HostConfigPhase{
+	KindMacAddresses:   PhaseIdentity,
+	KindRole:           PhaseClassification,
+	KindRootDeviceHint: PhaseHardware,
+	KindNetworkConfig:  PhaseNetwork,
+	KindBareMetalHost:  PhaseNetwork,
+}
+
+// kindPredecessors declares, for each kind, the kinds that must be applied
+// to a host before it. New kinds (such as ignition overrides) extend this
+// graph instead of relying on filename lexical order.
+var kindPredecessors = map[HostConfigKind][]HostConfigKind{
+	KindRole:           {KindMacAddresses},
+	KindRootDeviceHint: {KindRole},
+	KindNetworkConfig:  {KindRootDeviceHint},
+	KindBareMetalHost:  {KindNetworkConfig},
+}
+
+// HostConfigEntry is a single file to apply to a host.
+type HostConfigEntry struct {
+	Host    string
+	Path    string
+	Content []byte
+	Kind    HostConfigKind
+}
+
+// HostConfigPlan is an ordered, dependency-aware view of a HostConfigFileMap:
+// entries are grouped into phases and returned in a stable topological order,
+// so the agent installer client can apply them safely across a host reboot
+// mid-provisioning instead of relying on map iteration or filename sorting.
+type HostConfigPlan struct {
+	phases []hostConfigPhaseEntries
+}
+
+type hostConfigPhaseEntries struct {
+	phase   HostConfigPhase
+	entries []HostConfigEntry
+}
+
+// NewHostConfigPlan builds a HostConfigPlan from the same host config files
+// HostConfigFiles produces for a and, when non-nil, bmHosts, so the two
+// never drift apart on what files exist or what they contain.
+func NewHostConfigPlan(a *AgentConfig, bmHosts *AgentBareMetalHosts) (*HostConfigPlan, error) {
+	files, err := a.HostConfigFiles(bmHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	byKind := make(map[HostConfigKind][]HostConfigEntry)
+	for path, content := range files {
+		host := strings.SplitN(path, string(filepath.Separator), 2)[0]
+		kind := classifyHostConfigPath(path)
+		byKind[kind] = append(byKind[kind], HostConfigEntry{Host: host, Path: path, Content: content, Kind: kind})
+	}
+
+	ordered, err := topologicalKindOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &HostConfigPlan{}
+	for _, kind := range ordered {
+		entries := byKind[kind]
+		if len(entries) == 0 {
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+		plan.appendToPhase(kindPhase[kind], entries)
+	}
+
+	return plan, nil
+}
+
+// classifyHostConfigPath maps a HostConfigFiles path to the kind of config
+// it represents.
+func classifyHostConfigPath(path string) HostConfigKind {
+	base := filepath.Base(path)
+	switch {
+	case base == "mac_addresses":
+		return KindMacAddresses
+	case base == "role":
+		return KindRole
+	case base == "root-device-hints.yaml":
+		return KindRootDeviceHint
+	case strings.HasSuffix(base, "-bmh.yaml"), strings.HasSuffix(base, "-bmc-secret.yaml"):
+		return KindBareMetalHost
+	default:
+		return KindNetworkConfig
+	}
+}
+
+func (p *HostConfigPlan) appendToPhase(phase HostConfigPhase, entries []HostConfigEntry) {
+	for i := range p.phases {
+		if p.phases[i].phase == phase {
+			p.phases[i].entries = append(p.phases[i].entries, entries...)
+			return
+		}
+	}
+	p.phases = append(p.phases, hostConfigPhaseEntries{phase: phase, entries: entries})
+}
+
+// topologicalKindOrder returns every known kind in a stable order that
+// respects kindPredecessors, using kindOrder to break ties so the result
+// does not depend on map iteration order.
+func topologicalKindOrder() ([]HostConfigKind, error) {
+	visited := make(map[HostConfigKind]bool)
+	visiting := make(map[HostConfigKind]bool)
+	var order []HostConfigKind
+
+	var visit func(kind HostConfigKind) error
+	visit = func(kind HostConfigKind) error {
+		if visited[kind] {
+			return nil
+		}
+		if visiting[kind] {
+			return errors.Errorf("cyclic dependency detected at host config kind %q", kind)
+		}
+		visiting[kind] = true
+
+		for _, dep := range kindPredecessors[kind] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		visiting[kind] = false
+		visited[kind] = true
+		order = append(order, kind)
+		return nil
+	}
+
+	for _, kind := range kindOrder {
+		if err := visit(kind); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// Walk calls fn for every entry in the plan, phase by phase, in the stable
+// order computed by NewHostConfigPlan. It stops at the first error returned
+// by fn and returns it wrapped with the entry's path.
+func (p *HostConfigPlan) Walk(fn func(phase HostConfigPhase, entry HostConfigEntry) error) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, phaseEntries := range p.phases {
+		for _, entry := range phaseEntries.entries {
+			if err := fn(phaseEntries.phase, entry); err != nil {
+				return errors.Wrapf(err, "failed to apply %s", entry.Path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Files returns every entry in the plan as a HostConfigFileMap, for callers
+// that only need the final set of files rather than the ordering.
+func (p *HostConfigPlan) Files() HostConfigFileMap {
+	files := HostConfigFileMap{}
+	_ = p.Walk(func(_ HostConfigPhase, entry HostConfigEntry) error {
+		files[entry.Path] = entry.Content
+		return nil
+	})
+	return files
+}
+
+func init() {
+	// Guard against kindOrder and kindPhase drifting apart as new kinds are
+	// added.
+	for _, kind := range kindOrder {
+		if _, ok := kindPhase[kind]; !ok {
+			panic(fmt.Sprintf("host config kind %q has no phase assigned", kind))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package agentconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+func testAgentConfigForPlan() *AgentConfig {
+	return &AgentConfig{
+		Config: &agent.Config{
+			Hosts: []agent.Host{
+				{
+					Hostname:   "master-1",
+					Role:       "master",
+					Interfaces: []agent.Interface{{Name: "eth0", MacAddress: "00:00:00:00:00:01"}},
+				},
+				{
+					Hostname:   "master-0",
+					Role:       "master",
+					Interfaces: []agent.Interface{{Name: "eth0", MacAddress: "00:00:00:00:00:00"}},
+				},
+			},
+		},
+	}
+}
+
+// pathsInPhase returns the ordered paths of entries belonging to phase.
+func pathsInPhase(plan *HostConfigPlan, phase HostConfigPhase) []string {
+	var paths []string
+	_ = plan.Walk(func(p HostConfigPhase, entry HostConfigEntry) error {
+		if p == phase {
+			paths = append(paths, entry.Path)
+		}
+		return nil
+	})
+	return paths
+}
+
+func TestNewHostConfigPlanStableOrder(t *testing.T) {
+	a := testAgentConfigForPlan()
+
+	var orders [][]string
+	for i := 0; i < 20; i++ {
+		plan, err := NewHostConfigPlan(a, nil)
+		require.NoError(t, err)
+
+		var paths []string
+		err = plan.Walk(func(_ HostConfigPhase, entry HostConfigEntry) error {
+			paths = append(paths, entry.Path)
+			return nil
+		})
+		require.NoError(t, err)
+		orders = append(orders, paths)
+	}
+
+	for i := 1; i < len(orders); i++ {
+		assert.Equal(t, orders[0], orders[i], "Walk order must be stable across repeated runs regardless of map iteration")
+	}
+}
+
+func TestNewHostConfigPlanPhaseOrder(t *testing.T) {
+	a := testAgentConfigForPlan()
+
+	plan, err := NewHostConfigPlan(a, nil)
+	require.NoError(t, err)
+
+	var phases []HostConfigPhase
+	seen := map[HostConfigPhase]bool{}
+	err = plan.Walk(func(phase HostConfigPhase, _ HostConfigEntry) error {
+		if !seen[phase] {
+			phases = append(phases, phase)
+			seen[phase] = true
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []HostConfigPhase{PhaseIdentity, PhaseClassification}, phases)
+}
+
+func TestNewHostConfigPlanEntriesSortedWithinPhase(t *testing.T) {
+	a := testAgentConfigForPlan()
+
+	plan, err := NewHostConfigPlan(a, nil)
+	require.NoError(t, err)
+
+	paths := pathsInPhase(plan, PhaseIdentity)
+	require.Len(t, paths, 2)
+	assert.Equal(t, "master-0/mac_addresses", paths[0])
+	assert.Equal(t, "master-1/mac_addresses", paths[1])
+}
@@ -0,0 +1,102 @@
+package agentconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+func TestValidateNetworkConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		host    agent.Host
+		wantErr string
+	}{
+		{
+			name: "no networkConfig is valid",
+			host: agent.Host{Hostname: "host-0"},
+		},
+		{
+			name: "missing prefix length is rejected",
+			host: agent.Host{
+				Hostname: "host-0",
+				NetworkConfig: networkConfigFor(`interfaces:
+- name: eth0
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.168.122.2
+`),
+			},
+			wantErr: "prefix length must be set",
+		},
+		{
+			name: "bond listing a member declared later in the file is valid",
+			host: agent.Host{
+				Hostname: "host-0",
+				NetworkConfig: networkConfigFor(`interfaces:
+- name: bond0
+  type: bond
+  link-aggregation:
+    mode: active-backup
+    port:
+    - eth0
+    - eth1
+- name: eth0
+  type: ethernet
+- name: eth1
+  type: ethernet
+`),
+			},
+		},
+		{
+			name: "bridge listing a member declared later in the file is valid",
+			host: agent.Host{
+				Hostname: "host-0",
+				NetworkConfig: networkConfigFor(`interfaces:
+- name: br0
+  type: linux-bridge
+  bridge:
+    port:
+    - name: eth0
+- name: eth0
+  type: ethernet
+`),
+			},
+		},
+		{
+			name: "bond listing an interface that is never declared is rejected",
+			host: agent.Host{
+				Hostname: "host-0",
+				NetworkConfig: networkConfigFor(`interfaces:
+- name: bond0
+  type: bond
+  link-aggregation:
+    mode: active-backup
+    port:
+    - eth0
+`),
+			},
+			wantErr: "interface is not declared in networkConfig.interfaces",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &AgentConfig{}
+			errs := a.validateNetworkConfig(field.NewPath("Hosts").Index(0), tc.host)
+
+			if tc.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.NotEmpty(t, errs)
+			assert.Contains(t, errs.ToAggregate().Error(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Config is the API for the agent-config.yaml file.
+type Config struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// RendezvousIP is the IP address of the node running the bootstrap process:
+	// a single IPv4 or IPv6 address, or a comma-separated IPv4/IPv6 dual-stack pair.
+	RendezvousIP string `json:"rendezvousIP,omitempty"`
+
+	// Hosts is the list of hosts to provision.
+	Hosts []Host `json:"hosts,omitempty"`
+
+	// AdditionalNTPSources specifies additional NTP sources to be configured on each host.
+	AdditionalNTPSources []string `json:"additionalNTPSources,omitempty"`
+}
+
+// Host defines per-host configuration for the agent-based installer.
+type Host struct {
+	// Hostname is the desired hostname for the host.
+	Hostname string `json:"hostname,omitempty"`
+
+	// Interfaces is used to identify the host whose configuration this entry applies to.
+	Interfaces []Interface `json:"interfaces,omitempty"`
+
+	// NetworkConfig is the host's network configuration in NMState format.
+	NetworkConfig NetworkConfig `json:"networkConfig,omitempty"`
+
+	// Role is the role of the host, either "master" or "worker".
+	Role string `json:"role,omitempty"`
+
+	// RootDeviceHints specifies the device to use as the installation disk.
+	RootDeviceHints RootDeviceHints `json:"rootDeviceHints,omitempty"`
+
+	// BMC holds the host's baseboard management controller details, used to
+	// cross-reference the host across the agent-config and the generated
+	// BareMetalHost manifest.
+	BMC BMC `json:"bmc,omitempty"`
+}
+
+// Interface identifies a host network interface.
+type Interface struct {
+	Name       string `json:"name,omitempty"`
+	MacAddress string `json:"macAddress,omitempty"`
+}
+
+// NetworkConfig holds a host's network configuration in NMState format.
+type NetworkConfig struct {
+	runtime.RawExtension `json:",inline"`
+}
+
+// RootDeviceHints defines host properties to help identify the disk to use
+// for the installation.
+type RootDeviceHints struct {
+	DeviceName         string `json:"deviceName,omitempty"`
+	WWNWithExtension   string `json:"wwnWithExtension,omitempty"`
+	WWNVendorExtension string `json:"wwnVendorExtension,omitempty"`
+}
+
+// BMC holds baseboard management controller connection details for a host.
+type BMC struct {
+	Address string `json:"address,omitempty"`
+}
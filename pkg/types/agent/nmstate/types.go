@@ -0,0 +1,80 @@
+// Package nmstate contains the typed representation of the subset of the
+// NMState (https://nmstate.io) schema that the agent installer validates
+// at agent-config load time. It is intentionally versioned as its own Go
+// package so that new NMState fields can be added without changing the
+// wire format of agent.Host.NetworkConfig, which remains a RawExtension.
+package nmstate
+
+// Config is the typed form of a host's networkConfig block.
+type Config struct {
+	Interfaces  []Interface `json:"interfaces,omitempty"`
+	DNSResolver DNSResolver `json:"dns-resolver,omitempty"`
+	Routes      Routes      `json:"routes,omitempty"`
+}
+
+// Interface describes a single NMState network interface and its
+// associated addressing and sub-configuration.
+type Interface struct {
+	Name       string   `json:"name,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	State      string   `json:"state,omitempty"`
+	MacAddress string   `json:"mac-address,omitempty"`
+	IPv4       IPConfig `json:"ipv4,omitempty"`
+	IPv6       IPConfig `json:"ipv6,omitempty"`
+	Bond       *Bond    `json:"link-aggregation,omitempty"`
+	Vlan       *Vlan    `json:"vlan,omitempty"`
+	Bridge     *Bridge  `json:"bridge,omitempty"`
+}
+
+// IPConfig is the ipv4 or ipv6 block of an interface.
+type IPConfig struct {
+	Enabled bool      `json:"enabled,omitempty"`
+	DHCP    bool      `json:"dhcp,omitempty"`
+	Address []Address `json:"address,omitempty"`
+}
+
+// Address is a single static IP address assigned to an interface.
+// PrefixLength is a pointer so that an omitted prefix-length (nil) can be
+// told apart from an explicit 0.
+type Address struct {
+	IP           string `json:"ip,omitempty"`
+	PrefixLength *int   `json:"prefix-length,omitempty"`
+}
+
+// Bond is the link-aggregation sub-configuration of a bond interface.
+type Bond struct {
+	Mode string   `json:"mode,omitempty"`
+	Port []string `json:"port,omitempty"`
+}
+
+// Vlan is the vlan sub-configuration of a vlan interface.
+type Vlan struct {
+	BaseIface string `json:"base-iface,omitempty"`
+	ID        int    `json:"id,omitempty"`
+}
+
+// Bridge is the bridge sub-configuration of a bridge interface.
+type Bridge struct {
+	Port []struct {
+		Name string `json:"name,omitempty"`
+	} `json:"port,omitempty"`
+}
+
+// Routes holds the static routing configuration.
+type Routes struct {
+	Config []Route `json:"config,omitempty"`
+}
+
+// Route is a single static route.
+type Route struct {
+	Destination  string `json:"destination,omitempty"`
+	NextHopAddr  string `json:"next-hop-address,omitempty"`
+	NextHopIface string `json:"next-hop-interface,omitempty"`
+}
+
+// DNSResolver is the dns-resolver configuration block.
+type DNSResolver struct {
+	Config struct {
+		Server []string `json:"server,omitempty"`
+	} `json:"config,omitempty"`
+}